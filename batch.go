@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// maxUniqueAttemptsPerItem caps retries when -unique can't find a fresh
+// value, so a too-small pool (or too-large n) fails loudly instead of
+// looping forever.
+const maxUniqueAttemptsPerItem = 10000
+
+// Options configures a single generation call, covering both password
+// and passphrase modes. It's the shared configuration used by GenerateN
+// and the CLI.
+type Options struct {
+	Mode string // "password" or "passphrase"
+
+	// Password mode (see genPwd).
+	Length  int
+	Chars   []rune
+	Exclude string
+	Mins    map[rune]int
+	Human   bool
+
+	// Passphrase mode (see genPassphrase).
+	Words      int
+	Sep        string
+	Passphrase PassphraseOptions
+
+	// GuessRate is the assumed brute-force rate in guesses/sec, used
+	// when stats are reported for a Result. Zero uses DefaultGuessRate.
+	GuessRate float64
+}
+
+// Result is one item produced by GenerateN: either a generated value or
+// an error explaining why generation failed.
+type Result struct {
+	Value string
+	Err   error
+}
+
+// generateOne produces a single password or passphrase according to
+// opts.Mode.
+func generateOne(opts Options, src ...Source) (string, error) {
+	switch opts.Mode {
+	case "passphrase":
+		return genPassphrase(opts.Words, opts.Sep, opts.Passphrase, src...)
+	case "password":
+		return genPwd(opts.Length, opts.Chars, opts.Exclude, opts.Mins, opts.Human, src...)
+	default:
+		return "", fmt.Errorf("pwg: unknown mode %q, want \"password\" or \"passphrase\"", opts.Mode)
+	}
+}
+
+// GenerateN generates n passwords or passphrases according to opts,
+// streaming each as it's produced so callers don't have to wait for the
+// whole batch. If unique is true, duplicate values are regenerated
+// until a fresh one is produced (capped by maxUniqueAttemptsPerItem).
+//
+// The returned channel is closed after n results, or earlier if ctx is
+// canceled; an in-flight item may still be delivered once generation
+// is interrupted, but no further items are started.
+func GenerateN(ctx context.Context, n int, opts Options, unique bool, src ...Source) <-chan Result {
+	out := make(chan Result)
+	go func() {
+		defer close(out)
+		seen := make(map[string]bool, n)
+
+		for i := 0; i < n; i++ {
+			if ctx.Err() != nil {
+				return
+			}
+
+			value, err := generateOne(opts, src...)
+			if unique {
+				for attempt := 0; err == nil && seen[value]; attempt++ {
+					if attempt >= maxUniqueAttemptsPerItem {
+						err = fmt.Errorf("pwg: couldn't generate a unique value after %d attempts", maxUniqueAttemptsPerItem)
+						break
+					}
+					if ctx.Err() != nil {
+						return
+					}
+					value, err = generateOne(opts, src...)
+				}
+				if err == nil {
+					seen[value] = true
+				}
+			}
+
+			select {
+			case out <- Result{Value: value, Err: err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,84 @@
+package main
+
+import "strings"
+
+// Character classes used to build password pools. Each class is
+// selected individually via the -inc flag (and, for the per-class
+// minimums, the -min-* flags) using the same single-letter options:
+// 'l' for lowercase, 'u' for uppercase, 'n' for numbers, 's' for symbols.
+var (
+	lowercaseLetters = []rune{'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j', 'k', 'l', 'm', 'n', 'o', 'p', 'q', 'r', 's', 't', 'u', 'v', 'w', 'x', 'y', 'z'}
+	uppercaseLetters = []rune{'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z'}
+	numbers          = []rune{'1', '2', '3', '4', '5', '6', '7', '8', '9', '0'}
+	symbols          = []rune{'~', '`', '!', '@', '#', '$', '%', '^', '&', '*', '(', ')', '_', '-', '+', '=', '{', '[', '}', ']', '|', '\\', ':', ';', '"', '\'', '<', ',', '>', '.', '?', '/'}
+)
+
+// Human-readable variants of the character classes above, used by the
+// -human flag. They drop glyphs that are easily confused when read or
+// transcribed by hand: '0'/'O', '1'/'l'/'I', and symbols that are hard to
+// tell apart in most fonts.
+var (
+	lowercaseLettersHuman = []rune{'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'j', 'k', 'm', 'n', 'p', 'q', 'r', 's', 't', 'u', 'v', 'w', 'x', 'y', 'z'}
+	uppercaseLettersHuman = []rune{'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'J', 'K', 'M', 'N', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z'}
+	numbersHuman          = []rune{'2', '3', '4', '5', '6', '7', '8', '9'}
+	symbolsHuman          = []rune{'!', '@', '#', '$', '%', '^', '&', '*', '(', ')', '_', '-', '+', '='}
+)
+
+// classPool returns the predefined rune pool for a single class option
+// ('l', 'u', 'n', or 's'), or nil if opt isn't a recognized class. When
+// human is true, the reduced ambiguous-character-free pool is returned.
+func classPool(opt rune, human bool) []rune {
+	switch opt {
+	case 'l':
+		if human {
+			return lowercaseLettersHuman
+		}
+		return lowercaseLetters
+	case 'u':
+		if human {
+			return uppercaseLettersHuman
+		}
+		return uppercaseLetters
+	case 'n':
+		if human {
+			return numbersHuman
+		}
+		return numbers
+	case 's':
+		if human {
+			return symbolsHuman
+		}
+		return symbols
+	default:
+		return nil
+	}
+}
+
+// excludeRunes returns the subset of pool that doesn't appear in exc.
+func excludeRunes(pool []rune, exc string) []rune {
+	if exc == "" {
+		return pool
+	}
+	out := make([]rune, 0, len(pool))
+	for _, r := range pool {
+		if !strings.ContainsRune(exc, r) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// classIncluded reports whether any rune from pool is present in chars,
+// i.e. whether the class pool contributed to the compiled character set.
+func classIncluded(chars, pool []rune) bool {
+	in := make(map[rune]bool, len(pool))
+	for _, r := range pool {
+		in[r] = true
+	}
+	for _, c := range chars {
+		if in[c] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func countClass(pwd string, pool []rune) int {
+	set := make(map[rune]bool, len(pool))
+	for _, r := range pool {
+		set[r] = true
+	}
+	n := 0
+	for _, r := range pwd {
+		if set[r] {
+			n++
+		}
+	}
+	return n
+}
+
+func TestGenPwdMinPlacement(t *testing.T) {
+	tests := []struct {
+		name   string
+		length int
+		inc    string
+		mins   map[rune]int
+	}{
+		{
+			name:   "min digit and symbol",
+			length: 12,
+			inc:    "luns",
+			mins:   map[rune]int{'n': 3, 's': 2},
+		},
+		{
+			name:   "min upper only",
+			length: 8,
+			inc:    "luns",
+			mins:   map[rune]int{'u': 4},
+		},
+		{
+			name:   "all classes at minimum",
+			length: 10,
+			inc:    "luns",
+			mins:   map[rune]int{'l': 2, 'u': 2, 'n': 2, 's': 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chars := compileChars(CharOptions{Include: tt.inc})
+			pwd, err := genPwd(tt.length, chars, "", tt.mins, false)
+			if err != nil {
+				t.Fatalf("genPwd() unexpected error: %v", err)
+			}
+			if len(pwd) != tt.length {
+				t.Fatalf("genPwd() length = %d, want %d", len(pwd), tt.length)
+			}
+			for opt, n := range tt.mins {
+				got := countClass(pwd, classPool(opt, false))
+				if got < n {
+					t.Errorf("genPwd() has %d chars from class %q, want at least %d", got, opt, n)
+				}
+			}
+		})
+	}
+}
+
+func TestGenPwdMinsExceedLength(t *testing.T) {
+	chars := compileChars(CharOptions{Include: "luns"})
+	mins := map[rune]int{'l': 5, 'u': 5, 'n': 5}
+	_, err := genPwd(8, chars, "", mins, false)
+	if err == nil {
+		t.Fatal("genPwd() error = nil, want an error when sum of minimums exceeds length")
+	}
+}
+
+func TestGenPwdMinForExcludedClass(t *testing.T) {
+	chars := compileChars(CharOptions{Include: "lu"})
+	mins := map[rune]int{'n': 1}
+	_, err := genPwd(8, chars, "", mins, false)
+	if err == nil {
+		t.Fatal("genPwd() error = nil, want an error when a minimum names a class that isn't included")
+	}
+}
+
+func TestGenPwdMinForFullyExcludedChars(t *testing.T) {
+	chars := compileChars(CharOptions{Include: "luns"})
+	exc := string(numbers)
+	mins := map[rune]int{'n': 1}
+	_, err := genPwd(8, chars, exc, mins, false)
+	if err == nil {
+		t.Fatal("genPwd() error = nil, want an error when -exc excludes all of a minimum's class")
+	}
+}
+
+func TestGenPwdRespectsExclude(t *testing.T) {
+	chars := compileChars(CharOptions{Include: "luns"})
+	exc := "abcXYZ"
+	pwd, err := genPwd(20, chars, exc, nil, false)
+	if err != nil {
+		t.Fatalf("genPwd() unexpected error: %v", err)
+	}
+	for _, c := range exc {
+		if strings.ContainsRune(pwd, c) {
+			t.Errorf("genPwd() = %q, contains excluded character %q", pwd, c)
+		}
+	}
+}
@@ -1,63 +1,61 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
-	"math/rand/v2"
+	"os"
+	"os/signal"
 	"slices"
 	"strings"
+
+	"jasonparkertoo/pwg/pwn"
 )
 
 const (
-	DefaultPasswordLength = 12
+	DefaultPasswordLength  = 12
+	DefaultPassphraseWords = 6
+	DefaultPassphraseSep   = "-"
 )
 
+// CharOptions selects which character classes compileChars draws from.
+type CharOptions struct {
+	// Include specifies which character types to include: 'l' for
+	// lowercase letters, 'u' for uppercase letters, 'n' for numbers,
+	// and 's' for symbols. An empty Include means all classes.
+	Include string
+
+	// Human swaps in the reduced, ambiguous-character-free pools (see
+	// the -human flag) instead of the full character classes.
+	Human bool
+}
+
 // compileChars creates a slice of runes based on the specified character types.
 //
 // Parameters:
-//   - includes: A string specifying which character types to include.
-//     'l' for lowercase letters, 'u' for uppercase letters,
-//     'n' for numbers, and 's' for symbols.
+//   - opts: Which character classes to include, and whether to use their
+//     human-readable (ambiguous-character-free) variants.
+//   - src: An optional Source used to shuffle the result. Defaults to
+//     DefaultSource when omitted.
 //
 // Returns:
-//   A shuffled slice of runes containing the specified character types.
-//
-// If the 'includes' string is empty, all character types are included.
-// The function uses predefined slices for each character type and
-// combines them based on the 'includes' string. The resulting slice
-// is shuffled before being returned.
-func compileChars(includes string) []rune {
-	var (
-		LowercaseLetters = []rune{'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j', 'k', 'l', 'm', 'n', 'o', 'p', 'q', 'r', 's', 't', 'u', 'v', 'w', 'x', 'y', 'z'}
-		UppercaseLetters = []rune{'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z'}
-		Numbers          = []rune{'1', '2', '3', '4', '5', '6', '7', '8', '9', '0'}
-		Symbols          = []rune{'~', '`', '!', '@', '#', '$', '%', '^', '&', '*', '(', ')', '_', '-', '+', '=', '{', '[', '}', ']', '|', '\\', ':', ';', '"', '\'', '<', ',', '>', '.', '?', '/'}
-	)
-
+//
+//	A shuffled slice of runes containing the specified character types.
+//
+// If opts.Include is empty, all character types are included. The
+// resulting slice is shuffled before being returned.
+func compileChars(opts CharOptions, src ...Source) []rune {
 	var chars []rune
 
-	// add all characters if none are requested
+	includes := opts.Include
 	if len(includes) == 0 {
-		chars = append(chars, LowercaseLetters...)
-		chars = append(chars, UppercaseLetters...)
-		chars = append(chars, Numbers...)
-		chars = append(chars, Symbols...)
-		return shuffle(chars)
+		includes = "luns"
 	}
 
 	for _, opt := range includes {
-		switch opt {
-		case 'l':
-			chars = append(chars, LowercaseLetters...)
-		case 'u':
-			chars = append(chars, UppercaseLetters...)
-		case 'n':
-			chars = append(chars, Numbers...)
-		case 's':
-			chars = append(chars, Symbols...)
-		}
+		chars = append(chars, classPool(opt, opts.Human)...)
 	}
-	return shuffle(chars)
+	return shuffle(chars, src...)
 }
 
 // genPwd generates a random password based on specified criteria.
@@ -66,76 +64,286 @@ func compileChars(includes string) []rune {
 //   - length: The desired length of the password.
 //   - chars: A slice of runes representing the character set to use for generation.
 //   - exc: A string containing characters to exclude from the password.
+//   - mins: A map from class option ('l', 'u', 'n', 's') to the minimum
+//     number of characters from that class the password must contain.
+//     A nil or empty map imposes no minimums.
+//   - human: Whether the minimum characters should be drawn from the
+//     human-readable (ambiguous-character-free) class pools. This should
+//     match whatever produced chars.
+//   - src: An optional Source of randomness. Defaults to DefaultSource
+//     (crypto/rand backed) when omitted.
 //
 // Returns:
-//   A string representing the generated password.
 //
-// The function generates a password by randomly selecting characters from the
-// provided character set (chars), ensuring that the password meets the specified
-// length and does not include any characters listed in the exclude string (exc).
-func genPwd(length int, chars []rune, exc string) string {
+//	A string representing the generated password, or an error if the
+//	minimums can't be satisfied: their sum exceeds length, or one of
+//	them names a class that isn't part of chars or is exhausted by exc.
+//
+// The function first places the required minimum characters from each
+// class at random positions, fills the remaining positions from the
+// merged character set (chars), and finally shuffles the whole result
+// so the guaranteed characters aren't clustered at predictable offsets.
+func genPwd(length int, chars []rune, exc string, mins map[rune]int, human bool, src ...Source) (string, error) {
+	rng := source(src)
+
+	var total int
+	for opt, n := range mins {
+		if n <= 0 {
+			continue
+		}
+		if !classIncluded(chars, classPool(opt, human)) {
+			return "", fmt.Errorf("pwg: minimum requested for class %q, but it isn't included", opt)
+		}
+		if len(excludeRunes(classPool(opt, human), exc)) == 0 {
+			return "", fmt.Errorf("pwg: minimum requested for class %q, but -exc excludes all of its characters", opt)
+		}
+		total += n
+	}
+	if total > length {
+		return "", fmt.Errorf("pwg: sum of minimums (%d) exceeds password length (%d)", total, length)
+	}
+
 	pwd := make([]rune, length)
-	var i int
-	for i < length {
-		char := chars[rand.IntN(len(chars))]
-		if strings.ContainsRune(exc, char) {
+	reserved := make([]bool, length)
+	positions := shuffle(indexes(length), src...)
+
+	next := 0
+	for opt, n := range mins {
+		if n <= 0 {
 			continue
 		}
-		pwd[i] = char
-		i++
+		pool := excludeRunes(classPool(opt, human), exc)
+		for i := 0; i < n; i++ {
+			pos := positions[next]
+			next++
+			pwd[pos] = pool[rng.IntN(len(pool))]
+			reserved[pos] = true
+		}
+	}
+
+	for i := 0; i < length; i++ {
+		if reserved[i] {
+			continue
+		}
+		for {
+			char := chars[rng.IntN(len(chars))]
+			if strings.ContainsRune(exc, char) {
+				continue
+			}
+			pwd[i] = char
+			break
+		}
+	}
+
+	return string(shuffle(pwd, src...)), nil
+}
+
+// indexes returns the slice []int{0, 1, ..., n-1}.
+func indexes(n int) []int {
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
 	}
-	return string(pwd)
+	return idx
 }
 
-// shuffle randomizes the order of elements in a rune slice.
+// shuffle randomizes the order of elements in a slice.
 //
-// It takes a slice of runes as input and returns a new slice with the same
+// It takes a slice as input and returns a new slice with the same
 // elements in a randomized order. The original slice is not modified.
 //
 // Parameters:
-//   - r: The input slice of runes to be shuffled.
+//   - r: The input slice to be shuffled.
+//   - src: An optional Source of randomness. Defaults to DefaultSource
+//     (crypto/rand backed) when omitted.
 //
 // Returns:
-//   A new slice of runes with the elements in a randomized order.
-func shuffle(r []rune) []rune {
+//
+//	A new slice with the elements in a randomized order.
+func shuffle[T any](r []T, src ...Source) []T {
+	rng := source(src)
 	out := slices.Clone(r)
-	rand.Shuffle(len(out), func(i, j int) {
+	for i := len(out) - 1; i > 0; i-- {
+		j := rng.IntN(i + 1)
 		out[i], out[j] = out[j], out[i]
-	})
+	}
 	return out
 }
 
-// genpass generates random passwords based on specified criteria.
+// genpass generates random passwords or passphrases based on specified criteria.
 //
 // Usage:
-//   pwg [flags]
+//
+//	pwg [flags]
 //
 // Flags:
-//   -len int
-//         password length (default 12)
-//   -inc string
-//         characters to include: l,n,u,s for lowercase, uppercase, numbers, symbols respectively (default "l,u,n,s")
-//   -exc string
-//         list characters to exclude
+//
+//	-mode string
+//	      generation mode: "password" or "passphrase" (default "password")
+//	-len int
+//	      password length, for -mode password (default 12)
+//	-inc string
+//	      characters to include: l,n,u,s for lowercase, uppercase, numbers, symbols respectively (default "l,u,n,s")
+//	-exc string
+//	      list characters to exclude
+//	-min-lower int
+//	      minimum number of lowercase letters required (default 0)
+//	-min-upper int
+//	      minimum number of uppercase letters required (default 0)
+//	-min-digit int
+//	      minimum number of digits required (default 0)
+//	-min-symbol int
+//	      minimum number of symbols required (default 0)
+//	-words int
+//	      number of words, for -mode passphrase (default 6)
+//	-sep string
+//	      word separator, for -mode passphrase (default "-")
+//	-cap
+//	      capitalize each word, for -mode passphrase
+//	-inject-digit
+//	      append a random digit to a random word, for -mode passphrase
+//	-inject-symbol
+//	      append a random symbol to a random word, for -mode passphrase
+//	-check-pwned
+//	      check the generated password against the Have I Been Pwned range API
+//	-on-pwned string
+//	      action when -check-pwned finds a match: "regen", "warn", or "fail" (default "regen")
+//	-human, -H
+//	      use human-readable character sets that avoid ambiguous glyphs (0/O, 1/l/I, ...)
+//	-stats
+//	      print entropy and a brute-force strength estimate
+//	-guess-rate float
+//	      assumed brute-force guesses/sec, for -stats (default 1e10)
+//	-n int
+//	      number of passwords (or passphrases) to generate (default 1)
+//	-unique
+//	      guarantee no duplicates across a -n batch
 //
 // Examples:
-//   Generate a default password:
-//     pwg
 //
-//   Generate a 16-character password:
-//     pwg -len 16
+//	Generate a default password:
+//	  pwg
+//
+//	Generate a 16-character password:
+//	  pwg -len 16
+//
+//	Generate a password with only lowercase letters and numbers:
+//	  pwg -inc l,n
 //
-//   Generate a password with only lowercase letters and numbers:
-//     pwg -inc l,n
+//	Generate a password excluding specific characters:
+//	  pwg -exc "0O1Il"
 //
-//   Generate a password excluding specific characters:
-//     pwg -exc "0O1Il"
+//	Generate a password with at least one digit and one symbol:
+//	  pwg -min-digit 1 -min-symbol 1
+//
+//	Generate an 8-word passphrase:
+//	  pwg -mode passphrase -words 8
+//
+//	Generate a human-readable password avoiding ambiguous characters:
+//	  pwg -human
+//
+//	Generate a password and see how strong it is:
+//	  pwg -stats
+//
+//	Generate 5 unique passwords:
+//	  pwg -n 5 -unique
 //
 // Note: If no inclusion options are specified, all character types will be used.
 func main() {
+	mode := flag.String("mode", "password", `generation mode: "password" or "passphrase"`)
 	length := flag.Int("len", DefaultPasswordLength, "password length")
 	include := flag.String("inc", "l,u,n,s", "l,n,u,s for lowercase, uppercase, numbers, symbols respectively")
 	exclude := flag.String("exc", "", "list characters to exclude")
+	minLower := flag.Int("min-lower", 0, "minimum number of lowercase letters required")
+	minUpper := flag.Int("min-upper", 0, "minimum number of uppercase letters required")
+	minDigit := flag.Int("min-digit", 0, "minimum number of digits required")
+	minSymbol := flag.Int("min-symbol", 0, "minimum number of symbols required")
+	words := flag.Int("words", DefaultPassphraseWords, "number of words, for -mode passphrase")
+	sep := flag.String("sep", DefaultPassphraseSep, "word separator, for -mode passphrase")
+	capWords := flag.Bool("cap", false, "capitalize each word, for -mode passphrase")
+	injectDigit := flag.Bool("inject-digit", false, "append a random digit to a random word, for -mode passphrase")
+	injectSymbol := flag.Bool("inject-symbol", false, "append a random symbol to a random word, for -mode passphrase")
+	checkPwned := flag.Bool("check-pwned", false, "check the generated password against the Have I Been Pwned range API")
+	onPwned := flag.String("on-pwned", "regen", `action when -check-pwned finds a match: "regen", "warn", or "fail"`)
+	var human bool
+	flag.BoolVar(&human, "human", false, "use human-readable character sets that avoid ambiguous glyphs (0/O, 1/l/I, ...)")
+	flag.BoolVar(&human, "H", false, "shorthand for -human")
+	stats := flag.Bool("stats", false, "print entropy and a brute-force strength estimate")
+	guessRate := flag.Float64("guess-rate", DefaultGuessRate, "assumed brute-force guesses/sec, for -stats")
+	n := flag.Int("n", 1, "number of passwords (or passphrases) to generate")
+	unique := flag.Bool("unique", false, "guarantee no duplicates across a -n batch")
 	flag.Parse()
-	fmt.Println(genPwd(*length, compileChars(*include), *exclude))
+
+	if *mode != "password" && *mode != "passphrase" {
+		fmt.Fprintf(os.Stderr, "pwg: unknown -mode %q, want \"password\" or \"passphrase\"\n", *mode)
+		os.Exit(1)
+	}
+	if *checkPwned && *onPwned != "regen" && *onPwned != "warn" && *onPwned != "fail" {
+		fmt.Fprintf(os.Stderr, "pwg: unknown -on-pwned %q, want \"regen\", \"warn\", or \"fail\"\n", *onPwned)
+		os.Exit(1)
+	}
+
+	opts := Options{
+		Mode:    *mode,
+		Length:  *length,
+		Exclude: *exclude,
+		Mins: map[rune]int{
+			'l': *minLower,
+			'u': *minUpper,
+			'n': *minDigit,
+			's': *minSymbol,
+		},
+		Human: human,
+		Words: *words,
+		Sep:   *sep,
+		Passphrase: PassphraseOptions{
+			Capitalize:   *capWords,
+			InjectDigit:  *injectDigit,
+			InjectSymbol: *injectSymbol,
+		},
+		GuessRate: *guessRate,
+	}
+	if opts.Mode == "password" {
+		opts.Chars = compileChars(CharOptions{Include: *include, Human: human})
+	}
+
+	var checker pwn.Checker
+	if *checkPwned {
+		checker = pwn.NewHIBPChecker()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	for res := range GenerateN(ctx, *n, opts, *unique) {
+		if res.Err != nil {
+			fmt.Fprintln(os.Stderr, res.Err)
+			os.Exit(1)
+		}
+
+		value := res.Value
+		if checker != nil {
+			var err error
+			value, err = ensureNotPwned(checker, value, *onPwned, func() (string, error) { return generateOne(opts) })
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		}
+
+		fmt.Println(value)
+		switch opts.Mode {
+		case "passphrase":
+			s := newStats(value, opts.Words, len(wordlist), opts.GuessRate)
+			printEntropy(s.EntropyBits)
+			if *stats {
+				printStrength(s)
+			}
+		case "password":
+			if *stats {
+				pool := excludeRunes(opts.Chars, opts.Exclude)
+				printStats(newStats(value, opts.Length, len(pool), opts.GuessRate))
+			}
+		}
+	}
 }
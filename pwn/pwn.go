@@ -0,0 +1,86 @@
+// Package pwn checks candidate passwords against the Have I Been Pwned
+// (HIBP) breach corpus using the k-anonymity range API, so a generated
+// password can never be leaked to the API in full.
+package pwn
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// rangeURL is the HIBP k-anonymity endpoint. Callers send only the first
+// five hex characters of the password's SHA-1 hash.
+const rangeURL = "https://api.pwnedpasswords.com/range/"
+
+// Checker reports whether a password has appeared in a known breach.
+type Checker interface {
+	Check(password string) (bool, error)
+}
+
+// HTTPClient is the subset of *http.Client used by HIBPChecker, so tests
+// can inject a fake transport instead of hitting the network.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// HIBPChecker is a Checker backed by the HIBP range API.
+type HIBPChecker struct {
+	// Client performs the HTTP request. Defaults to http.DefaultClient
+	// if nil.
+	Client HTTPClient
+
+	// AddPadding requests that HIBP pad its response with decoy hashes,
+	// making the response size harder to use as a side channel.
+	AddPadding bool
+}
+
+// NewHIBPChecker returns a HIBPChecker that queries the HIBP range API
+// over http.DefaultClient, with response padding enabled.
+func NewHIBPChecker() *HIBPChecker {
+	return &HIBPChecker{Client: http.DefaultClient, AddPadding: true}
+}
+
+// Check reports whether password appears in the HIBP breach corpus. Only
+// the first five hex characters of its SHA-1 hash ever leave the process.
+func (c *HIBPChecker) Check(password string) (bool, error) {
+	hash := fmt.Sprintf("%X", sha1.Sum([]byte(password)))
+	prefix, suffix := hash[:5], hash[5:]
+
+	req, err := http.NewRequest(http.MethodGet, rangeURL+prefix, nil)
+	if err != nil {
+		return false, fmt.Errorf("pwn: building request: %w", err)
+	}
+	if c.AddPadding {
+		req.Header.Set("Add-Padding", "true")
+	}
+
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("pwn: querying HIBP: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("pwn: HIBP returned status %d", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		suf, _, ok := strings.Cut(line, ":")
+		if ok && suf == suffix {
+			return true, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("pwn: reading HIBP response: %w", err)
+	}
+	return false, nil
+}
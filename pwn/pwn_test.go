@@ -0,0 +1,98 @@
+package pwn
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// roundTripFunc adapts a function to the HTTPClient interface so tests
+// can fake HIBP responses without a real network call.
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func canned(status int, body string) HTTPClient {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: status,
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}, nil
+	})
+}
+
+func TestHIBPCheckerCheck(t *testing.T) {
+	// SHA-1("password") = 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8,
+	// split into the "5BAA6" prefix and "1E4C9B93F3F0682250B6CF8331B7EE68FD8" suffix.
+	const suffix = "1E4C9B93F3F0682250B6CF8331B7EE68FD8"
+
+	tests := []struct {
+		name    string
+		body    string
+		status  int
+		want    bool
+		wantErr bool
+	}{
+		{
+			name:   "suffix present",
+			body:   "0000000000000000000000000000000000:1\r\n" + suffix + ":3533661\r\nAAAA0000000000000000000000000000000:2\r\n",
+			status: http.StatusOK,
+			want:   true,
+		},
+		{
+			name:   "suffix absent",
+			body:   "0000000000000000000000000000000000:1\r\nAAAA0000000000000000000000000000000:2\r\n",
+			status: http.StatusOK,
+			want:   false,
+		},
+		{
+			name:    "non-200 status",
+			body:    "",
+			status:  http.StatusInternalServerError,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &HIBPChecker{Client: canned(tt.status, tt.body)}
+			got, err := c.Check("password")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Check() error = nil, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Check() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("Check() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHIBPCheckerRequest(t *testing.T) {
+	var gotURL, gotPadding string
+	client := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		gotURL = req.URL.String()
+		gotPadding = req.Header.Get("Add-Padding")
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+	})
+
+	c := &HIBPChecker{Client: client, AddPadding: true}
+	if _, err := c.Check("password"); err != nil {
+		t.Fatalf("Check() unexpected error: %v", err)
+	}
+
+	if want := rangeURL + "5BAA6"; gotURL != want {
+		t.Errorf("request URL = %q, want %q", gotURL, want)
+	}
+	if gotPadding != "true" {
+		t.Errorf("Add-Padding header = %q, want %q", gotPadding, "true")
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"jasonparkertoo/pwg/pwn"
+)
+
+// maxPwnedRetries caps regeneration attempts under -on-pwned=regen so a
+// pathological RNG or character set can't loop forever.
+const maxPwnedRetries = 10
+
+// ensureNotPwned checks pwd against checker and, if it's been seen in a
+// known breach, handles it according to mode:
+//
+//   - "regen": generate a replacement (up to maxPwnedRetries times) and
+//     check again.
+//   - "warn": print a warning to stderr and keep pwd.
+//   - "fail": return an error instead of a password.
+func ensureNotPwned(checker pwn.Checker, pwd string, mode string, generate func() (string, error)) (string, error) {
+	for attempt := 0; attempt < maxPwnedRetries; attempt++ {
+		pwned, err := checker.Check(pwd)
+		if err != nil {
+			return "", fmt.Errorf("pwg: checking HIBP: %w", err)
+		}
+		if !pwned {
+			return pwd, nil
+		}
+
+		switch mode {
+		case "warn":
+			fmt.Fprintln(os.Stderr, "pwg: warning: generated password has appeared in a known breach")
+			return pwd, nil
+		case "fail":
+			return "", fmt.Errorf("pwg: generated password has appeared in a known breach")
+		case "regen":
+			pwd, err = generate()
+			if err != nil {
+				return "", err
+			}
+		default:
+			return "", fmt.Errorf("pwg: unknown -on-pwned %q, want \"regen\", \"warn\", or \"fail\"", mode)
+		}
+	}
+	return "", fmt.Errorf("pwg: exceeded %d attempts to generate a password absent from known breaches", maxPwnedRetries)
+}
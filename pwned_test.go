@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// fakeChecker reports the n-th password passed to Check as pwned
+// according to pwned[n], cycling the last entry once exhausted.
+type fakeChecker struct {
+	pwned []bool
+	calls int
+}
+
+func (f *fakeChecker) Check(password string) (bool, error) {
+	i := f.calls
+	if i >= len(f.pwned) {
+		i = len(f.pwned) - 1
+	}
+	f.calls++
+	return f.pwned[i], nil
+}
+
+func TestEnsureNotPwnedRegen(t *testing.T) {
+	checker := &fakeChecker{pwned: []bool{true, true, false}}
+	attempts := 0
+	generate := func() (string, error) {
+		attempts++
+		return "candidate", nil
+	}
+
+	got, err := ensureNotPwned(checker, "password", "regen", generate)
+	if err != nil {
+		t.Fatalf("ensureNotPwned() unexpected error: %v", err)
+	}
+	if got != "candidate" {
+		t.Errorf("ensureNotPwned() = %q, want %q", got, "candidate")
+	}
+	if attempts != 2 {
+		t.Errorf("generate called %d times, want 2", attempts)
+	}
+}
+
+func TestEnsureNotPwnedRegenExhausted(t *testing.T) {
+	checker := &fakeChecker{pwned: []bool{true}}
+	generate := func() (string, error) { return "candidate", nil }
+
+	_, err := ensureNotPwned(checker, "password", "regen", generate)
+	if err == nil {
+		t.Fatal("ensureNotPwned() error = nil, want an error after exhausting retries")
+	}
+}
+
+func TestEnsureNotPwnedWarn(t *testing.T) {
+	checker := &fakeChecker{pwned: []bool{true}}
+	generate := func() (string, error) {
+		t.Fatal("generate should not be called in warn mode")
+		return "", nil
+	}
+
+	got, err := ensureNotPwned(checker, "password", "warn", generate)
+	if err != nil {
+		t.Fatalf("ensureNotPwned() unexpected error: %v", err)
+	}
+	if got != "password" {
+		t.Errorf("ensureNotPwned() = %q, want original password kept", got)
+	}
+}
+
+func TestEnsureNotPwnedFail(t *testing.T) {
+	checker := &fakeChecker{pwned: []bool{true}}
+	generate := func() (string, error) {
+		t.Fatal("generate should not be called in fail mode")
+		return "", nil
+	}
+
+	_, err := ensureNotPwned(checker, "password", "fail", generate)
+	if err == nil {
+		t.Fatal("ensureNotPwned() error = nil, want an error in fail mode")
+	}
+}
+
+func TestEnsureNotPwnedNotPwned(t *testing.T) {
+	checker := &fakeChecker{pwned: []bool{false}}
+	generate := func() (string, error) {
+		t.Fatal("generate should not be called when not pwned")
+		return "", nil
+	}
+
+	got, err := ensureNotPwned(checker, "password", "regen", generate)
+	if err != nil {
+		t.Fatalf("ensureNotPwned() unexpected error: %v", err)
+	}
+	if got != "password" {
+		t.Errorf("ensureNotPwned() = %q, want %q", got, "password")
+	}
+}
+
+func TestEnsureNotPwnedGenerateError(t *testing.T) {
+	checker := &fakeChecker{pwned: []bool{true}}
+	wantErr := errors.New("boom")
+	generate := func() (string, error) { return "", wantErr }
+
+	_, err := ensureNotPwned(checker, "password", "regen", generate)
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("ensureNotPwned() error = %v, want it to wrap %v", err, wantErr)
+	}
+}
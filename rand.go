@@ -0,0 +1,47 @@
+package main
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+)
+
+// Source supplies random integers in the range [0, n) for password
+// generation. It mirrors the IntN method of math/rand/v2.Rand so that
+// either a crypto/rand-backed source or a seeded math/rand/v2 source can
+// be injected, which is useful for deterministic tests.
+type Source interface {
+	IntN(n int) int
+}
+
+// DefaultSource is the Source used when callers don't provide their own.
+// It draws from crypto/rand, since generated passwords are credentials
+// and shouldn't depend on a non-cryptographic PRNG.
+var DefaultSource Source = cryptoSource{}
+
+// cryptoSource implements Source using crypto/rand.
+type cryptoSource struct{}
+
+// IntN returns a cryptographically secure random int in [0, n).
+// It panics if n <= 0 or if the system CSPRNG fails, mirroring the
+// panic behavior of math/rand/v2's IntN for the same inputs.
+func (cryptoSource) IntN(n int) int {
+	if n <= 0 {
+		panic("pwg: Source.IntN: argument must be positive")
+	}
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		panic(fmt.Sprintf("pwg: crypto/rand: %v", err))
+	}
+	return int(i.Int64())
+}
+
+// source returns the first Source in src, or DefaultSource if none was
+// given. It lets genPwd, compileChars, and shuffle take an optional
+// trailing Source argument without burdening the common case.
+func source(src []Source) Source {
+	if len(src) > 0 {
+		return src[0]
+	}
+	return DefaultSource
+}
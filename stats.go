@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+)
+
+// DefaultGuessRate is the brute-force guesses-per-second assumed when no
+// rate is given: a generous offline attack rate against a fast hash.
+const DefaultGuessRate = 1e10
+
+// PasswordStats summarizes the strength of a generated password or
+// passphrase.
+type PasswordStats struct {
+	Password string
+
+	// PoolSize is the number of distinct symbols (characters, for a
+	// password; words, for a passphrase) the password was drawn from.
+	PoolSize int
+
+	// EntropyBits is the Shannon entropy of the password in bits,
+	// computed as (number of symbols) * log2(PoolSize).
+	EntropyBits float64
+
+	// GuessRate is the guesses-per-second used for BruteForceSecs.
+	GuessRate float64
+
+	// BruteForceSecs is the estimated average time, in seconds, to
+	// brute-force the password at GuessRate (half the keyspace).
+	BruteForceSecs float64
+
+	// Strength is a qualitative label: "weak", "fair", "strong", or
+	// "excellent".
+	Strength string
+}
+
+// GenerateOptions bundles the inputs needed to produce a password via
+// Generate and report on its strength.
+type GenerateOptions struct {
+	Length  int
+	Chars   []rune
+	Exclude string
+	Mins    map[rune]int
+	Human   bool
+
+	// GuessRate is the assumed brute-force rate in guesses/sec. Zero
+	// uses DefaultGuessRate.
+	GuessRate float64
+}
+
+// Generate produces a password from opts and returns it alongside
+// PasswordStats describing its strength.
+func Generate(opts GenerateOptions, src ...Source) (PasswordStats, error) {
+	pwd, err := genPwd(opts.Length, opts.Chars, opts.Exclude, opts.Mins, opts.Human, src...)
+	if err != nil {
+		return PasswordStats{}, err
+	}
+	pool := excludeRunes(opts.Chars, opts.Exclude)
+	return newStats(pwd, opts.Length, len(pool), opts.GuessRate), nil
+}
+
+// newStats builds a PasswordStats for a password or passphrase made of
+// count symbols drawn uniformly from a pool of poolSize options.
+func newStats(pwd string, count, poolSize int, guessRate float64) PasswordStats {
+	if guessRate <= 0 {
+		guessRate = DefaultGuessRate
+	}
+	bits := float64(count) * math.Log2(float64(poolSize))
+	return PasswordStats{
+		Password:       pwd,
+		PoolSize:       poolSize,
+		EntropyBits:    bits,
+		GuessRate:      guessRate,
+		BruteForceSecs: math.Pow(2, bits) / 2 / guessRate,
+		Strength:       strengthLabel(bits),
+	}
+}
+
+// strengthLabel gives a qualitative read on an entropy value in bits.
+func strengthLabel(bits float64) string {
+	switch {
+	case bits < 40:
+		return "weak"
+	case bits < 60:
+		return "fair"
+	case bits < 80:
+		return "strong"
+	default:
+		return "excellent"
+	}
+}
+
+// formatApproxDuration renders a duration in seconds as a short
+// human-readable approximation, scaling the unit up to years since
+// brute-force estimates routinely overflow time.Duration.
+func formatApproxDuration(seconds float64) string {
+	const (
+		minute = 60.0
+		hour   = 60 * minute
+		day    = 24 * hour
+		year   = 365.25 * day
+	)
+	switch {
+	case seconds < minute:
+		return fmt.Sprintf("%.2fs", seconds)
+	case seconds < hour:
+		return fmt.Sprintf("%.2fm", seconds/minute)
+	case seconds < day:
+		return fmt.Sprintf("%.2fh", seconds/hour)
+	case seconds < year:
+		return fmt.Sprintf("%.2f days", seconds/day)
+	default:
+		return fmt.Sprintf("%.2e years", seconds/year)
+	}
+}
+
+// printEntropy writes a password's entropy, in bits, to stderr, keeping
+// stdout limited to one generated secret per line.
+func printEntropy(bits float64) {
+	fmt.Fprintf(os.Stderr, "Entropy: %.2f bits\n", bits)
+}
+
+// printStrength writes a PasswordStats' qualitative strength and
+// estimated brute-force time to stderr, keeping stdout limited to one
+// generated secret per line.
+func printStrength(s PasswordStats) {
+	fmt.Fprintf(os.Stderr, "Strength: %s\n", s.Strength)
+	fmt.Fprintf(os.Stderr, "Estimated brute-force time at %.0e guesses/sec: %s\n", s.GuessRate, formatApproxDuration(s.BruteForceSecs))
+}
+
+// printStats writes a full PasswordStats report (entropy, strength, and
+// estimated brute-force time) to stderr, as used by the -stats flag.
+func printStats(s PasswordStats) {
+	printEntropy(s.EntropyBits)
+	printStrength(s)
+}